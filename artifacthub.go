@@ -0,0 +1,255 @@
+/*
+Copyright The Helm Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"helm.sh/helm/v3/cmd/helm/search"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+const defaultArtifactHubURL = "https://artifacthub.io"
+
+var (
+	useArtifactHub bool          = false
+	artifactHubURL string        = defaultArtifactHubURL
+	artifactHubTTL time.Duration = 1 * time.Hour
+)
+
+// artifactHubSearchResponse is the (trimmed) shape of
+// 'GET /api/v1/packages/search?kind=0&ts_query_web=<chart>'.
+type artifactHubSearchResponse struct {
+	Packages []struct {
+		Name       string `json:"name"`
+		Repository struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"repository"`
+	} `json:"packages"`
+}
+
+// artifactHubPackageResponse is the (trimmed) shape of
+// 'GET /packages/helm/{repo}/{chart}'.
+type artifactHubPackageResponse struct {
+	Version    string `json:"version"`
+	AppVersion string `json:"app_version"`
+	Deprecated bool   `json:"deprecated"`
+	ContentURL string `json:"content_url"`
+	Repository struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"repository"`
+}
+
+// artifactHubCache is the on-disk cache format, keyed by
+// 'artifactHubCacheKey(baseURL, chartName)' rather than the chart name
+// alone, so pointing '--artifacthub-url' at a different instance (e.g.
+// switching between the public artifacthub.io and an on-prem one) can never
+// serve that other instance's cached package data for the same chart name.
+type artifactHubCache struct {
+	Entries map[string]artifactHubCacheEntry `json:"entries"`
+}
+
+// artifactHubCacheKey derives the cache key for @chartName as looked up
+// against @baseURL.
+func artifactHubCacheKey(baseURL, chartName string) string {
+	return baseURL + "|" + chartName
+}
+
+type artifactHubCacheEntry struct {
+	FetchedAt time.Time                    `json:"fetched_at"`
+	Packages  []artifactHubPackageResponse `json:"packages"`
+}
+
+// artifactHubClient queries ArtifactHub for a chart that isn't present in
+// any locally configured repository, caching results on disk so repeated
+// runs of the plugin don't hammer the API.
+type artifactHubClient struct {
+	baseURL   string
+	ttl       time.Duration
+	cachePath string
+	http      *http.Client
+}
+
+func newArtifactHubClient(baseURL string, ttl time.Duration) (*artifactHubClient, error) {
+	cacheDir, err := artifactHubCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return &artifactHubClient{
+		baseURL:   baseURL,
+		ttl:       ttl,
+		cachePath: filepath.Join(cacheDir, "artifacthub.json"),
+		http:      &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func artifactHubCacheDir() (string, error) {
+	base := os.Getenv("XDG_CACHE_HOME")
+	if base == "" {
+		userCache, err := os.UserCacheDir()
+		if err != nil {
+			return "", errors.Wrap(err, "could not determine cache directory")
+		}
+		base = userCache
+	}
+
+	dir := filepath.Join(base, "helm-whatup")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", errors.Wrapf(err, "could not create cache directory %q", dir)
+	}
+
+	return dir, nil
+}
+
+// Lookup returns a synthetic search.Result for every ArtifactHub repository
+// serving @chartName, querying the API (and populating the on-disk cache)
+// if no fresh cache entry exists yet. Returning one result per repository
+// lets the caller feed them straight back into 'searchChart', which already
+// knows how to report duplicates across repositories serving the same
+// chart.
+func (c *artifactHubClient) Lookup(chartName string) ([]*search.Result, error) {
+	cache, err := c.loadCache()
+	if err != nil {
+		return nil, err
+	}
+
+	key := artifactHubCacheKey(c.baseURL, chartName)
+
+	entry, ok := cache.Entries[key]
+	if !ok || time.Since(entry.FetchedAt) >= c.ttl {
+		packages, err := c.fetch(chartName)
+		if err != nil {
+			return nil, err
+		}
+
+		entry = artifactHubCacheEntry{FetchedAt: time.Now(), Packages: packages}
+		cache.Entries[key] = entry
+
+		if err := c.saveCache(cache); err != nil {
+			// a cache write failure should not prevent the lookup from succeeding
+			debug("ArtifactHub: could not persist cache: %s", err.Error())
+		}
+	}
+
+	results := make([]*search.Result, 0, len(entry.Packages))
+	for _, pkg := range entry.Packages {
+		results = append(results, toSearchResult(chartName, pkg))
+	}
+
+	return results, nil
+}
+
+// fetch queries ArtifactHub for @chartName and resolves each matching
+// package to its full package details (version, appVersion, deprecation).
+func (c *artifactHubClient) fetch(chartName string) ([]artifactHubPackageResponse, error) {
+	searchURL := fmt.Sprintf("%s/api/v1/packages/search?kind=0&ts_query_web=%s", c.baseURL, url.QueryEscape(chartName))
+
+	var searchResp artifactHubSearchResponse
+	if err := c.getJSON(searchURL, &searchResp); err != nil {
+		return nil, errors.Wrapf(err, "ArtifactHub search for %q failed", chartName)
+	}
+
+	if len(searchResp.Packages) == 0 {
+		return nil, errors.Errorf("ArtifactHub has no package matching %q", chartName)
+	}
+
+	packages := make([]artifactHubPackageResponse, 0, len(searchResp.Packages))
+	for _, pkg := range searchResp.Packages {
+		packageURL := fmt.Sprintf("%s/api/v1/packages/helm/%s/%s", c.baseURL, pkg.Repository.Name, pkg.Name)
+
+		var pkgResp artifactHubPackageResponse
+		if err := c.getJSON(packageURL, &pkgResp); err != nil {
+			debug("ArtifactHub: could not fetch package %s/%s: %s", pkg.Repository.Name, pkg.Name, err.Error())
+			continue
+		}
+
+		packages = append(packages, pkgResp)
+	}
+
+	if len(packages) == 0 {
+		return nil, errors.Errorf("ArtifactHub package lookup for %q failed for every matching repository", chartName)
+	}
+
+	return packages, nil
+}
+
+func (c *artifactHubClient) getJSON(u string, v interface{}) error {
+	resp, err := c.http.Get(u)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status %d from %s", resp.StatusCode, u)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func toSearchResult(chartName string, pkg artifactHubPackageResponse) *search.Result {
+	return &search.Result{
+		Name: pkg.Repository.Name + "/" + chartName,
+		Chart: &repo.ChartVersion{
+			Metadata: &chart.Metadata{
+				Name:       chartName,
+				Version:    pkg.Version,
+				AppVersion: pkg.AppVersion,
+				Deprecated: pkg.Deprecated,
+			},
+			URLs: []string{pkg.ContentURL},
+		},
+	}
+}
+
+func (c *artifactHubClient) loadCache() (*artifactHubCache, error) {
+	cache := &artifactHubCache{Entries: map[string]artifactHubCacheEntry{}}
+
+	data, err := os.ReadFile(c.cachePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cache, nil
+		}
+		return nil, errors.Wrapf(err, "could not read ArtifactHub cache %q", c.cachePath)
+	}
+
+	if err := json.Unmarshal(data, cache); err != nil {
+		// a corrupt cache file should not break the command, just start fresh
+		debug("ArtifactHub: cache file %q is corrupt, ignoring: %s", c.cachePath, err.Error())
+		return &artifactHubCache{Entries: map[string]artifactHubCacheEntry{}}, nil
+	}
+
+	return cache, nil
+}
+
+func (c *artifactHubClient) saveCache(cache *artifactHubCache) error {
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(c.cachePath, data, 0o644)
+}
@@ -0,0 +1,268 @@
+/*
+Copyright The Helm Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestToSearchResult(t *testing.T) {
+	pkg := artifactHubPackageResponse{
+		Version:    "1.2.0",
+		AppVersion: "2.0.0",
+		Deprecated: true,
+		ContentURL: "https://charts.example.com/nginx-1.2.0.tgz",
+	}
+	pkg.Repository.Name = "bitnami"
+
+	got := toSearchResult("nginx", pkg)
+	if got.Name != "bitnami/nginx" {
+		t.Fatalf("toSearchResult().Name = %q, want %q", got.Name, "bitnami/nginx")
+	}
+	if got.Chart.Metadata.Version != "1.2.0" || got.Chart.Metadata.AppVersion != "2.0.0" || !got.Chart.Metadata.Deprecated {
+		t.Fatalf("unexpected metadata: %+v", got.Chart.Metadata)
+	}
+	if len(got.Chart.URLs) != 1 || got.Chart.URLs[0] != pkg.ContentURL {
+		t.Fatalf("unexpected URLs: %v", got.Chart.URLs)
+	}
+}
+
+func TestArtifactHubCacheRoundTrip(t *testing.T) {
+	c := &artifactHubClient{cachePath: filepath.Join(t.TempDir(), "artifacthub.json")}
+
+	loaded, err := c.loadCache()
+	if err != nil {
+		t.Fatalf("loadCache() on a missing file error = %v", err)
+	}
+	if len(loaded.Entries) != 0 {
+		t.Fatalf("expected an empty cache for a missing file, got %+v", loaded.Entries)
+	}
+
+	cache := &artifactHubCache{
+		Entries: map[string]artifactHubCacheEntry{
+			"nginx": {
+				FetchedAt: time.Now().Truncate(time.Second),
+				Packages: []artifactHubPackageResponse{
+					{Version: "1.2.0"},
+				},
+			},
+		},
+	}
+	if err := c.saveCache(cache); err != nil {
+		t.Fatalf("saveCache() error = %v", err)
+	}
+
+	got, err := c.loadCache()
+	if err != nil {
+		t.Fatalf("loadCache() after saveCache() error = %v", err)
+	}
+
+	entry, ok := got.Entries["nginx"]
+	if !ok || len(entry.Packages) != 1 || entry.Packages[0].Version != "1.2.0" {
+		t.Fatalf("loadCache() round-trip mismatch: %+v", got.Entries)
+	}
+	if !entry.FetchedAt.Equal(cache.Entries["nginx"].FetchedAt) {
+		t.Fatalf("FetchedAt = %v, want %v", entry.FetchedAt, cache.Entries["nginx"].FetchedAt)
+	}
+}
+
+func TestArtifactHubLoadCacheCorruptFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "artifacthub.json")
+	if err := os.WriteFile(path, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &artifactHubClient{cachePath: path}
+	got, err := c.loadCache()
+	if err != nil {
+		t.Fatalf("loadCache() on a corrupt file should not error, got %v", err)
+	}
+	if len(got.Entries) != 0 {
+		t.Fatalf("expected an empty cache for a corrupt file, got %+v", got.Entries)
+	}
+}
+
+// newArtifactHubTestServer serves a fixed search result for @chartName,
+// whose single matching repository reports @version, and increments @calls
+// on every request so tests can assert on cache-hit behavior.
+func newArtifactHubTestServer(t *testing.T, chartName, version string, calls *int32) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/packages/search", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		resp := artifactHubSearchResponse{}
+		resp.Packages = []struct {
+			Name       string `json:"name"`
+			Repository struct {
+				Name string `json:"name"`
+				URL  string `json:"url"`
+			} `json:"repository"`
+		}{{Name: chartName}}
+		resp.Packages[0].Repository.Name = "bitnami"
+		json.NewEncoder(w).Encode(resp)
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/v1/packages/helm/bitnami/%s", chartName), func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		json.NewEncoder(w).Encode(artifactHubPackageResponse{Version: version})
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestArtifactHubLookupCachesUntilTTLExpires(t *testing.T) {
+	var calls int32
+	srv := newArtifactHubTestServer(t, "nginx", "1.2.0", &calls)
+
+	c := &artifactHubClient{
+		baseURL:   srv.URL,
+		ttl:       200 * time.Millisecond,
+		cachePath: filepath.Join(t.TempDir(), "artifacthub.json"),
+		http:      srv.Client(),
+	}
+
+	if _, err := c.Lookup("nginx"); err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("expected 2 requests (search + package detail) on first Lookup, got %d", got)
+	}
+
+	if _, err := c.Lookup("nginx"); err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("expected the second Lookup within the TTL to be served from cache, got %d requests", got)
+	}
+
+	time.Sleep(250 * time.Millisecond)
+
+	if _, err := c.Lookup("nginx"); err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 4 {
+		t.Fatalf("expected the Lookup after TTL expiry to re-fetch, got %d requests", got)
+	}
+}
+
+// TestArtifactHubLookupKeyedByBaseURL guards the chunk0-2 fix: switching
+// '--artifacthub-url' between two instances that both happen to serve
+// "nginx" must never return the other instance's cached package data from
+// the shared on-disk cache file.
+func TestArtifactHubLookupKeyedByBaseURL(t *testing.T) {
+	var callsA, callsB int32
+	srvA := newArtifactHubTestServer(t, "nginx", "1.2.0", &callsA)
+	srvB := newArtifactHubTestServer(t, "nginx", "9.9.9", &callsB)
+
+	cachePath := filepath.Join(t.TempDir(), "artifacthub.json")
+
+	cA := &artifactHubClient{baseURL: srvA.URL, ttl: time.Hour, cachePath: cachePath, http: srvA.Client()}
+	cB := &artifactHubClient{baseURL: srvB.URL, ttl: time.Hour, cachePath: cachePath, http: srvB.Client()}
+
+	resultsA, err := cA.Lookup("nginx")
+	if err != nil {
+		t.Fatalf("Lookup() against instance A error = %v", err)
+	}
+	if len(resultsA) != 1 || resultsA[0].Chart.Metadata.Version != "1.2.0" {
+		t.Fatalf("unexpected results from instance A: %+v", resultsA)
+	}
+
+	resultsB, err := cB.Lookup("nginx")
+	if err != nil {
+		t.Fatalf("Lookup() against instance B error = %v", err)
+	}
+	if len(resultsB) != 1 || resultsB[0].Chart.Metadata.Version != "9.9.9" {
+		t.Fatalf("instance B returned instance A's cached package data: %+v", resultsB)
+	}
+
+	if got := atomic.LoadInt32(&callsB); got != 2 {
+		t.Fatalf("expected instance B to make its own requests instead of reusing instance A's cache entry, got %d requests", got)
+	}
+
+	// instance A must still see its own cached data, unaffected by B's Lookup.
+	resultsA2, err := cA.Lookup("nginx")
+	if err != nil {
+		t.Fatalf("second Lookup() against instance A error = %v", err)
+	}
+	if len(resultsA2) != 1 || resultsA2[0].Chart.Metadata.Version != "1.2.0" {
+		t.Fatalf("instance A's cache entry was clobbered by instance B: %+v", resultsA2)
+	}
+	if got := atomic.LoadInt32(&callsA); got != 2 {
+		t.Fatalf("expected instance A's second Lookup to be served from its own cache entry, got %d requests", got)
+	}
+}
+
+func TestArtifactHubFetchSkipsFailedPackageDetails(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/packages/search", func(w http.ResponseWriter, r *http.Request) {
+		resp := artifactHubSearchResponse{}
+		resp.Packages = []struct {
+			Name       string `json:"name"`
+			Repository struct {
+				Name string `json:"name"`
+				URL  string `json:"url"`
+			} `json:"repository"`
+		}{
+			{Name: "nginx"},
+			{Name: "nginx"},
+		}
+		resp.Packages[0].Repository.Name = "broken"
+		resp.Packages[1].Repository.Name = "bitnami"
+		json.NewEncoder(w).Encode(resp)
+	})
+	mux.HandleFunc("/api/v1/packages/helm/broken/nginx", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/api/v1/packages/helm/bitnami/nginx", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(artifactHubPackageResponse{Version: "1.2.0"})
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c := &artifactHubClient{baseURL: srv.URL, http: srv.Client()}
+
+	packages, err := c.fetch("nginx")
+	if err != nil {
+		t.Fatalf("fetch() error = %v", err)
+	}
+	if len(packages) != 1 || packages[0].Version != "1.2.0" {
+		t.Fatalf("expected fetch() to skip the failed repository and return the rest, got %+v", packages)
+	}
+}
+
+func TestArtifactHubFetchNoMatches(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(artifactHubSearchResponse{})
+	}))
+	t.Cleanup(srv.Close)
+
+	c := &artifactHubClient{baseURL: srv.URL, http: srv.Client()}
+
+	if _, err := c.fetch("nginx"); err == nil {
+		t.Fatal("expected an error when ArtifactHub has no matching package")
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright The Helm Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+
+	"helm.sh/helm/v3/cmd/helm/search"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+func newTestResult(name, version string) *search.Result {
+	return &search.Result{
+		Name: name,
+		Chart: &repo.ChartVersion{
+			Metadata: &chart.Metadata{
+				Name:    name,
+				Version: version,
+			},
+		},
+	}
+}
+
+func TestChartIndexKey(t *testing.T) {
+	cases := map[string]string{
+		"stable/nginx": "nginx",
+		"nginx":        "nginx",
+		"Stable/NGINX": "nginx",
+	}
+
+	for in, want := range cases {
+		if got := chartIndexKey(in); got != want {
+			t.Errorf("chartIndexKey(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestBuildChartIndex(t *testing.T) {
+	results := []*search.Result{
+		newTestResult("stable/nginx", "1.0.0"),
+		newTestResult("bitnami/nginx", "1.1.0"),
+		newTestResult("stable/redis", "1.0.0"),
+	}
+
+	idx := buildChartIndex(results)
+	if len(idx["nginx"]) != 2 {
+		t.Fatalf("expected 2 results for 'nginx', got %d", len(idx["nginx"]))
+	}
+	if len(idx["redis"]) != 1 {
+		t.Fatalf("expected 1 result for 'redis', got %d", len(idx["redis"]))
+	}
+}
+
+func TestMergeChartIndex(t *testing.T) {
+	idx := buildChartIndex([]*search.Result{newTestResult("stable/nginx", "1.0.0")})
+
+	results := []*search.Result{
+		newTestResult("stable/nginx", "1.0.0"),
+		newTestResult("argocd-nginx/nginx", "1.2.0"),
+	}
+
+	mergeChartIndex(idx, results, "nginx")
+	if len(idx["nginx"]) != 2 {
+		t.Fatalf("expected mergeChartIndex to pick up the newly registered repo, got %d entries", len(idx["nginx"]))
+	}
+}
+
+// TestSearchChartResultsDoesNotLeakAcrossCacheKeys guards the chunk0-5 fix:
+// a fallback result found for one release must not be visible to another
+// release that merely shares the same bare chart name but a different
+// (chart, version, devel) cache key.
+func TestSearchChartResultsDoesNotLeakAcrossCacheKeys(t *testing.T) {
+	chartIndex := map[string][]*search.Result{}
+
+	// release A's OCI fallback result, scoped locally the way
+	// newOutdatedListWriter now builds it.
+	ociResult := newTestResult("ghcr.io/acme/nginx", "2.0.0")
+	scoped := append(append([]*search.Result{}, chartIndex["nginx"]...), ociResult)
+
+	resA, depA, err := searchChartResults(scoped, "nginx", "1.0.0", false)
+	if err != nil || !depA || resA.Type != CHART {
+		t.Fatalf("unexpected result for release A: %+v dep=%v err=%v", resA, depA, err)
+	}
+
+	// chartIndex itself must be untouched by the scoped lookup above.
+	if len(chartIndex["nginx"]) != 0 {
+		t.Fatalf("expected chartIndex to remain empty, got %d entries (fallback leaked into shared index)", len(chartIndex["nginx"]))
+	}
+
+	// release B shares the chart name but not the installed version, and
+	// never resolved an OCI ref of its own: it must still miss.
+	_, depB, errB := searchChart(chartIndex, "nginx", "1.5.0", false)
+	if errB == nil || depB {
+		t.Fatalf("release B unexpectedly saw release A's OCI result: dep=%v err=%v", depB, errB)
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright The Helm Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// colorMode is the raw value of the '--color' flag.
+var colorMode string = "auto"
+
+const (
+	ansiReset  = "\x1b[0m"
+	ansiRed    = "\x1b[31m"
+	ansiYellow = "\x1b[33m"
+	ansiGreen  = "\x1b[32m"
+)
+
+// colorEnabled resolves '--color' against @out: "always"/"never" are taken
+// literally, "auto" colorizes only if @out is a terminal.
+func colorEnabled(out io.Writer) bool {
+	switch colorMode {
+	case "always":
+		return true
+	case "never":
+		return false
+	default:
+		f, ok := out.(*os.File)
+		if !ok {
+			return false
+		}
+
+		return term.IsTerminal(int(f.Fd()))
+	}
+}
+
+// severityColor returns the ANSI color code for @b, or "" for no color.
+func severityColor(b bumpLevel) string {
+	switch b {
+	case bumpMajor:
+		return ansiRed
+	case bumpMinor:
+		return ansiYellow
+	case bumpPatch:
+		return ansiGreen
+	default:
+		return ""
+	}
+}
+
+// severityLabel returns the plain-text, ANSI-free SEVERITY cell value so
+// scripts can still grep the level when '--color=never' is set.
+func severityLabel(b bumpLevel) string {
+	switch b {
+	case bumpMajor:
+		return "MAJOR"
+	case bumpMinor:
+		return "MINOR"
+	case bumpPatch:
+		return "PATCH"
+	default:
+		return ""
+	}
+}
+
+// colorize wraps @s in @code if both @enabled is true and @code is non-empty.
+func colorize(enabled bool, code, s string) string {
+	if !enabled || code == "" {
+		return s
+	}
+
+	return code + s + ansiReset
+}
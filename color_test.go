@@ -0,0 +1,65 @@
+/*
+Copyright The Helm Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestColorEnabled(t *testing.T) {
+	t.Cleanup(func() { colorMode = "auto" })
+
+	colorMode = "always"
+	if !colorEnabled(&bytes.Buffer{}) {
+		t.Error("--color=always should enable color even for a non-terminal writer")
+	}
+
+	colorMode = "never"
+	if colorEnabled(&bytes.Buffer{}) {
+		t.Error("--color=never should disable color")
+	}
+
+	colorMode = "auto"
+	if colorEnabled(&bytes.Buffer{}) {
+		t.Error("--color=auto should disable color for a non-*os.File writer")
+	}
+}
+
+func TestSeverityLabel(t *testing.T) {
+	cases := map[bumpLevel]string{
+		bumpMajor: "MAJOR",
+		bumpMinor: "MINOR",
+		bumpPatch: "PATCH",
+		bumpNone:  "",
+	}
+
+	for level, want := range cases {
+		if got := severityLabel(level); got != want {
+			t.Errorf("severityLabel(%q) = %q, want %q", level, got, want)
+		}
+	}
+}
+
+func TestColorize(t *testing.T) {
+	if got := colorize(true, ansiRed, "x"); got != ansiRed+"x"+ansiReset {
+		t.Errorf("colorize(true, ...) = %q, want wrapped output", got)
+	}
+	if got := colorize(false, ansiRed, "x"); got != "x" {
+		t.Errorf("colorize(false, ...) = %q, want unwrapped output", got)
+	}
+	if got := colorize(true, "", "x"); got != "x" {
+		t.Errorf("colorize(true, \"\", ...) = %q, want unwrapped output for an empty code", got)
+	}
+}
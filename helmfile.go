@@ -0,0 +1,147 @@
+/*
+Copyright The Helm Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+
+	"helm.sh/helm/v3/cmd/helm/search"
+)
+
+// helmfileRepository mirrors the 'repositories:' block of a helmfile.yaml.
+type helmfileRepository struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url"`
+}
+
+// helmfileRelease mirrors one entry of the 'releases:' block of a
+// helmfile.yaml. Only the fields this plugin cares about are parsed; a
+// helmfile.yaml usually carries many more (values, set, condition, ...).
+type helmfileRelease struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace"`
+	Chart     string `yaml:"chart"`
+	Version   string `yaml:"version"`
+}
+
+// helmfileDoc is the (trimmed) shape of a single helmfile.yaml.
+type helmfileDoc struct {
+	Repositories []helmfileRepository `yaml:"repositories"`
+	Releases     []helmfileRelease    `yaml:"releases"`
+}
+
+// helmfileReleaseSource discovers releases from one or more helmfile.yaml
+// manifests instead of a live cluster, so CI pipelines can lint pinned
+// chart versions without kubeconfig access.
+type helmfileReleaseSource struct {
+	path  string
+	index *search.Index
+	out   io.Writer
+}
+
+func newHelmfileReleaseSource(path string, index *search.Index, out io.Writer) *helmfileReleaseSource {
+	return &helmfileReleaseSource{path: path, index: index, out: out}
+}
+
+func (s *helmfileReleaseSource) Load() ([]releaseSeed, error) {
+	files, err := helmfileFiles(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var seeds []releaseSeed
+	for _, file := range files {
+		doc, err := parseHelmfile(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not parse helmfile %q", file)
+		}
+
+		for _, repository := range doc.Repositories {
+			if indexHasRepo(s.index, repository.Name) {
+				continue
+			}
+
+			if err := addRepoToIndex(s.index, repository.Name, repository.URL); err != nil {
+				fmt.Fprintf(s.out, "WARNING: Could not register repo %q from %q (skipping): %s\n", repository.Name, file, err.Error())
+			}
+		}
+
+		for _, release := range doc.Releases {
+			// 'chart:' is either a bare chart name or "repoAlias/chartName" (e.g. "stable/nginx")
+			chartName := release.Chart
+			if idx := strings.LastIndex(chartName, "/"); idx != -1 {
+				chartName = chartName[idx+1:]
+			}
+
+			seeds = append(seeds, releaseSeed{
+				Name:         release.Name,
+				Namespace:    release.Namespace,
+				Chart:        chartName,
+				ChartVersion: release.Version,
+			})
+		}
+	}
+
+	return seeds, nil
+}
+
+// helmfileFiles resolves @path to the list of helmfile manifests to parse:
+// the file itself, or every *.yaml/*.yml file directly inside it if it is a
+// directory.
+func helmfileFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not stat --helmfile path %q", path)
+	}
+
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	for _, ext := range []string{"*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(path, ext))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+
+	if len(files) == 0 {
+		return nil, errors.Errorf("no helmfile manifests found in directory %q", path)
+	}
+
+	return files, nil
+}
+
+func parseHelmfile(file string) (*helmfileDoc, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &helmfileDoc{}
+	if err := yaml.Unmarshal(data, doc); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
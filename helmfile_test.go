@@ -0,0 +1,163 @@
+/*
+Copyright The Helm Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"helm.sh/helm/v3/cmd/helm/search"
+)
+
+func TestHelmfileFilesSingleFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "helmfile.yaml")
+	if err := os.WriteFile(path, []byte("releases: []\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := helmfileFiles(path)
+	if err != nil {
+		t.Fatalf("helmfileFiles() error = %v", err)
+	}
+	if len(files) != 1 || files[0] != path {
+		t.Fatalf("helmfileFiles() = %v, want [%s]", files, path)
+	}
+}
+
+func TestHelmfileFilesDirectory(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.yaml", "b.yml", "c.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("releases: []\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := helmfileFiles(dir)
+	if err != nil {
+		t.Fatalf("helmfileFiles() error = %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("helmfileFiles() = %v, want 2 yaml/yml files (not the .txt one)", files)
+	}
+}
+
+func TestHelmfileFilesEmptyDirectory(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := helmfileFiles(dir); err == nil {
+		t.Fatal("expected an error for a directory with no helmfile manifests")
+	}
+}
+
+func TestHelmfileFilesMissingPath(t *testing.T) {
+	if _, err := helmfileFiles(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Fatal("expected an error for a nonexistent --helmfile path")
+	}
+}
+
+func TestParseHelmfile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "helmfile.yaml")
+	content := `
+repositories:
+  - name: stable
+    url: https://charts.helm.sh/stable
+
+releases:
+  - name: my-nginx
+    namespace: web
+    chart: stable/nginx
+    version: 1.2.3
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := parseHelmfile(path)
+	if err != nil {
+		t.Fatalf("parseHelmfile() error = %v", err)
+	}
+
+	if len(doc.Repositories) != 1 || doc.Repositories[0].Name != "stable" || doc.Repositories[0].URL != "https://charts.helm.sh/stable" {
+		t.Fatalf("unexpected repositories: %+v", doc.Repositories)
+	}
+
+	if len(doc.Releases) != 1 {
+		t.Fatalf("expected 1 release, got %d", len(doc.Releases))
+	}
+
+	r := doc.Releases[0]
+	if r.Name != "my-nginx" || r.Namespace != "web" || r.Chart != "stable/nginx" || r.Version != "1.2.3" {
+		t.Fatalf("unexpected release: %+v", r)
+	}
+}
+
+// TestHelmfileReleaseSourceLoad guards the chunk0-3 compile fix: Load() must
+// actually register a helmfile's 'repositories:' block (via indexHasRepo /
+// addRepoToIndex) and normalize its 'releases:' into releaseSeeds.
+func TestHelmfileReleaseSourceLoad(t *testing.T) {
+	srv := newTestIndexServer(t, "1.2.0")
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "helmfile.yaml")
+	content := `
+repositories:
+  - name: stable
+    url: ` + srv.URL + `
+
+releases:
+  - name: my-nginx
+    namespace: web
+    chart: stable/nginx
+    version: 1.0.0
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	index := search.NewIndex()
+	out := &bytes.Buffer{}
+
+	seeds, err := newHelmfileReleaseSource(path, index, out).Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(seeds) != 1 {
+		t.Fatalf("Load() = %v, want exactly 1 release", seeds)
+	}
+
+	got := seeds[0]
+	if got.Name != "my-nginx" || got.Namespace != "web" || got.Chart != "nginx" || got.ChartVersion != "1.0.0" {
+		t.Fatalf("unexpected seed: %+v", got)
+	}
+
+	idx := buildChartIndex(index.All())
+	if len(idx["nginx"]) != 1 {
+		t.Fatalf("expected the 'stable' repo to be registered in the index, got %d 'nginx' entries", len(idx["nginx"]))
+	}
+
+	// Loading again (e.g. a second helmfile referencing the same repo name)
+	// must not re-register it.
+	if _, err := newHelmfileReleaseSource(path, index, out).Load(); err != nil {
+		t.Fatalf("second Load() error = %v", err)
+	}
+	idx = buildChartIndex(index.All())
+	if len(idx["nginx"]) != 1 {
+		t.Fatalf("expected indexHasRepo to dedupe the already-registered 'stable' repo, got %d 'nginx' entries", len(idx["nginx"]))
+	}
+}
@@ -36,7 +36,6 @@ import (
 	"helm.sh/helm/v3/pkg/cli"
 	"helm.sh/helm/v3/pkg/cli/output"
 	"helm.sh/helm/v3/pkg/helmpath"
-	"helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/repo"
 )
 
@@ -72,6 +71,15 @@ var (
 	version         string
 	deprecationInfo bool // deprecationInfo describes if the "DEPRECTATION" notice will be printed or not
 
+	sourceFlag   string // sourceFlag is the raw value of the '--source' flag, one of "helm", "argocd" or "all"
+	helmfilePath string // helmfilePath is the value of the '--helmfile' flag, empty if unset
+
+	// argocdNamespace is the namespace Argo CD 'Application' CRs are
+	// discovered in. It defaults to "", i.e. cluster-wide, since Applications
+	// almost always live in a dedicated control-plane namespace (e.g.
+	// "argocd") rather than the '-n' namespace releases are deployed to.
+	argocdNamespace string
+
 	// repoDuplicates
 )
 
@@ -86,30 +94,99 @@ func newOutdatedCmd(cfg *action.Configuration, out io.Writer) *cobra.Command {
 		Aliases: []string{"od"},
 		Args:    require.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if client.AllNamespaces {
-				if err := cfg.Init(settings.RESTClientGetter(), "", os.Getenv("HELM_DRIVER"), debug); err != nil {
-					return err
-				}
-			}
-			client.SetStateMask()
-
-			releases, err := client.Run()
+			devel, err := cmd.Flags().GetBool("devel")
 			if err != nil {
 				return err
 			}
 
-			devel, err := cmd.Flags().GetBool("devel")
+			if colorMode != "auto" && colorMode != "always" && colorMode != "never" {
+				return errors.Errorf("invalid --color %q: must be one of auto, always, never", colorMode)
+			}
+
+			index, err := buildSearchIndex(out, devel)
 			if err != nil {
+				// TODO: Find a better way to exit
+				fmt.Fprintf(out, "%s", errors.Wrap(err, "ERROR: Could not initialize search index").Error())
+				os.Exit(1)
+			}
+
+			var seeds []releaseSeed
+
+			if helmfilePath != "" {
+				// helmfile mode skips the cluster entirely, so it is mutually exclusive with '--source'.
+				helmfileSeeds, err := newHelmfileReleaseSource(helmfilePath, index, out).Load()
+				if err != nil {
+					return err
+				}
+				seeds = append(seeds, helmfileSeeds...)
+			} else {
+				src := releaseSource(sourceFlag)
+				if src != sourceHelm && src != sourceArgoCD && src != sourceAll {
+					return errors.Errorf("invalid --source %q: must be one of helm, argocd, all", sourceFlag)
+				}
+
+				if src == sourceHelm || src == sourceAll {
+					if client.AllNamespaces {
+						if err := cfg.Init(settings.RESTClientGetter(), "", os.Getenv("HELM_DRIVER"), debug); err != nil {
+							return err
+						}
+					}
+					client.SetStateMask()
+
+					releases, err := client.Run()
+					if err != nil {
+						return err
+					}
+
+					helmSeeds, err := newHelmReleaseSource(releases).Load()
+					if err != nil {
+						return err
+					}
+					seeds = append(seeds, helmSeeds...)
+				}
+
+				if src == sourceArgoCD || src == sourceAll {
+					dynClient, err := newDynamicClientForArgo()
+					if err != nil {
+						return errors.Wrap(err, "could not discover Argo CD Applications")
+					}
+
+					argoSeeds, err := newArgoReleaseSource(dynClient, argocdNamespace, out).Load()
+					if err != nil {
+						return err
+					}
+					seeds = append(seeds, argoSeeds...)
+				}
+			}
+
+			writer := newOutdatedListWriter(seeds, index, out, devel)
+
+			if client.Short {
+				if err := writeShortOutput(out, outfmt, writer.releases); err != nil {
+					return err
+				}
+			} else if err := outfmt.Write(out, writer); err != nil {
 				return err
 			}
 
-			return outfmt.Write(out, newOutdatedListWriter(releases, cfg, out, devel))
+			return evaluatePolicy(writer.policyReleases())
 		},
 	}
 
 	flags := cmd.Flags()
 	flags.BoolVar(&deprecationInfo, "deprecation-notice", true, "disable it to prevent printing the deprecation notice message")
 	flags.BoolVar(&ignoreNoRepo, "ignore-repo", false, "ignore error if no repo for a chart is found")
+	flags.StringVar(&sourceFlag, "source", string(sourceHelm), "where to discover releases from. One of: helm, argocd, all")
+	flags.StringVar(&argocdNamespace, "argocd-namespace", "", "namespace to discover Argo CD Applications in when --source is argocd or all. Defaults to all namespaces")
+	flags.StringVar(&helmfilePath, "helmfile", "", "path to a helmfile.yaml (or a directory of them); skips the cluster and lints the pinned chart versions instead")
+	flags.BoolVar(&useArtifactHub, "use-artifacthub", false, "fall back to ArtifactHub if a chart isn't found in any configured repo")
+	flags.StringVar(&artifactHubURL, "artifacthub-url", defaultArtifactHubURL, "ArtifactHub instance to query when --use-artifacthub is set")
+	flags.DurationVar(&artifactHubTTL, "artifacthub-cache-ttl", artifactHubTTL, "how long to cache ArtifactHub lookups for")
+	flags.BoolVar(&failOnOutdated, "fail-on-outdated", false, "exit with a non-zero status if any release is outdated")
+	flags.StringVar(&failOnLevel, "fail-on", "", "exit with a non-zero status if any release has at least this severity of version bump available. One of: major, minor, patch")
+	flags.BoolVar(&failOnDeprecated, "fail-on-deprecated", false, "exit with a non-zero status if any release uses a deprecated chart")
+	flags.StringVar(&colorMode, "color", colorMode, "colorize the table output by severity. One of: auto, always, never")
+	flags.StringArrayVar(&ociRegistries, "oci-registry", nil, "OCI registry (e.g. 'ghcr.io/org/charts') to check for a release's chart if it carries no OCI provenance annotation. Can be repeated")
 	flags.Bool("devel", false, "use development versions (alpha, beta, and release candidate releases), too. Equivalent to version '>0.0.0-0'.")
 	flags.BoolVarP(&client.Short, "short", "q", false, "output short (quiet) listing format")
 	flags.BoolVarP(&client.ByDate, "date", "d", false, "sort by release date")
@@ -137,8 +214,9 @@ type outdatedElement struct {
 	LatestVer    string    `json:"latest_version"`
 	AppVer       string    `json:"app_version"` // AppVer does contain the App version defined in 'Chart.yaml'
 	Chart        string    `json:"chart"`
-	Updated      time.Time `json:"updated"`    // Updated contains the last time where the chart in the repository was updated
-	Deprecated   bool      `json:"deprecated"` // Deprecated does contain `deprecated` field from the Chart.yaml file
+	Updated      time.Time `json:"updated"`              // Updated contains the last time where the chart in the repository was updated
+	Deprecated   bool      `json:"deprecated"`           // Deprecated does contain `deprecated` field from the Chart.yaml file
+	BumpLevel    bumpLevel `json:"bump_level,omitempty"` // BumpLevel describes the semver severity of upgrading from InstalledVer to LatestVer
 }
 
 type repoDuplicate struct {
@@ -152,6 +230,20 @@ type outdatedListWriter struct {
 	repoDuplicates []repoDuplicate
 }
 
+// policyReleases returns every outdatedElement the report produced,
+// including the per-repo entries nested under repoDuplicates, so
+// '--fail-on*' and the JSON/YAML 'summary' object account for charts
+// served by more than one repo too, not just single-repo matches.
+func (r *outdatedListWriter) policyReleases() []outdatedElement {
+	all := make([]outdatedElement, 0, len(r.releases))
+	all = append(all, r.releases...)
+	for _, dup := range r.repoDuplicates {
+		all = append(all, dup.Repos...)
+	}
+
+	return all
+}
+
 type searchType uint8
 
 const (
@@ -167,42 +259,124 @@ type searchResult struct {
 	repos repoDuplicate  // repos will contain information if @Type is @REPOS
 }
 
-func newOutdatedListWriter(releases []*release.Release, cfg *action.Configuration, out io.Writer, devel bool) *outdatedListWriter {
-	outdated := make([]outdatedElement, 0, len(releases))
-	dups := make([]repoDuplicate, 0, len(releases))
+// searchCacheKey identifies a unique (chart, installed version, devel,
+// repoURL) tuple. Every release sharing a key (very common when many
+// releases deploy the same subchart) reuses the first release's search
+// instead of re-running it. repoURL is part of the key so that two releases
+// of the same bare chart name pinned to different Argo CD repoURLs (e.g.
+// two different "nginx" forks) are never conflated into the same cached
+// result.
+type searchCacheKey struct {
+	chart   string
+	version string
+	devel   bool
+	repoURL string
+}
 
-	// we initialize the Struct with default Options but the 'devel' option can be set by the User, all the other ones are not
-	// relevant.
-	searchRepo := searchRepoOptions{
-		versions:     false,
-		regexp:       false,
-		devel:        devel,
-		maxColWidth:  50,
-		version:      "",
-		repoFile:     settings.RepositoryConfig,
-		repoCacheDir: settings.RepositoryCache,
-	}
+type searchCacheEntry struct {
+	result searchResult
+	dep    bool
+	err    error
+}
 
-	// initialize Repo index first
-	index, err := initSearch(out, &searchRepo)
-	if err != nil {
-		// TODO: Find a better way to exit
-		fmt.Fprintf(out, "%s", errors.Wrap(err, "ERROR: Could not initialize search index").Error())
-		os.Exit(1)
+func newOutdatedListWriter(seeds []releaseSeed, index *search.Index, out io.Writer, devel bool) *outdatedListWriter {
+	outdated := make([]outdatedElement, 0, len(seeds))
+	dups := make([]repoDuplicate, 0, len(seeds))
+
+	var ahClient *artifactHubClient
+	if useArtifactHub {
+		var err error
+		ahClient, err = newArtifactHubClient(artifactHubURL, artifactHubTTL)
+		if err != nil {
+			fmt.Fprintf(out, "%s", errors.Wrap(err, "ERROR: Could not initialize ArtifactHub client").Error())
+			os.Exit(1)
+		}
 	}
 
-	// get all locally indexed charts
-	results := index.All()
+	// lazily initialized on the first release that actually needs an OCI lookup
+	var ociClient *ociResolver
+	ociInitFailed := false
+
+	// build the chart -> []*search.Result map once instead of re-scanning
+	// every indexed chart for every release.
+	chartIndex := buildChartIndex(index.All())
+	searchCache := make(map[searchCacheKey]searchCacheEntry)
+	registeredRepos := make(map[string]string) // repoURL -> synthetic repo name, so the same repo is only downloaded once
+
+	for _, r := range seeds {
+		// releases discovered outside of 'repositories.yaml' (e.g. Argo CD
+		// Applications) need their repo registered in the index before we
+		// can search it.
+		if r.RepoURL != "" {
+			if _, err := registerDynamicRepo(index, registeredRepos, r.RepoURL); err != nil {
+				fmt.Fprintf(out, "WARNING: Could not register repo %q for chart '%s' (skipping): %s\n", r.RepoURL, r.Chart, err.Error())
+				continue
+			}
+			mergeChartIndex(chartIndex, index.All(), r.Chart)
+		}
 
-	for _, r := range releases {
-		// search if it exists a newer Chart in the Chart-Repository
-		repoResult, dep, err := searchChart(results, r.Chart.Name(), r.Chart.Metadata.Version, devel)
+		key := searchCacheKey{chart: strings.ToLower(r.Chart), version: r.ChartVersion, devel: devel, repoURL: r.RepoURL}
+		cached, ok := searchCache[key]
+		if !ok {
+			repoResult, dep, err := searchChart(chartIndex, r.Chart, r.ChartVersion, devel)
+
+			if err != nil {
+				if candidates := ociRefCandidates(r.Chart, r.Annotations); len(candidates) > 0 {
+					if ociClient == nil && !ociInitFailed {
+						var ociInitErr error
+						ociClient, ociInitErr = newOCIResolver()
+						if ociInitErr != nil {
+							ociInitFailed = true
+							fmt.Fprintf(out, "WARNING: Could not initialize OCI registry client (skipping OCI lookups): %s\n", ociInitErr.Error())
+						}
+					}
+
+					for _, ref := range candidates {
+						if ociClient == nil {
+							break
+						}
+
+						ociResult, ociErr := ociClient.Resolve(ref, r.Chart, r.ChartVersion, devel)
+						if ociErr != nil {
+							debug("OCI: %s", ociErr.Error())
+							continue
+						}
+
+						// scoped to this release's own lookup: merging into
+						// the shared chartIndex would leak this ref to every
+						// other release sharing the same bare chart name.
+						scoped := append(append([]*search.Result{}, chartIndex[key.chart]...), ociResult)
+						repoResult, dep, err = searchChartResults(scoped, r.Chart, r.ChartVersion, devel)
+						break
+					}
+				}
+			}
+
+			if err != nil && ahClient != nil {
+				// local index (and any OCI registry) missed entirely, fall back to ArtifactHub before giving up on this release
+				ahResults, ahErr := ahClient.Lookup(r.Chart)
+				if ahErr != nil {
+					debug("ArtifactHub: %s", ahErr.Error())
+				} else {
+					// scoped the same way as the OCI fallback above: kept out
+					// of the shared chartIndex so it can't leak to another
+					// release's (different) cache-key lookup.
+					scoped := append(append([]*search.Result{}, chartIndex[key.chart]...), ahResults...)
+					repoResult, dep, err = searchChartResults(scoped, r.Chart, r.ChartVersion, devel)
+				}
+			}
+
+			cached = searchCacheEntry{result: repoResult, dep: dep, err: err}
+			searchCache[key] = cached
+		}
+
+		repoResult, dep, err := cached.result, cached.dep, cached.err
 		if err != nil {
 			if !ignoreNoRepo {
 				fmt.Fprintf(out, "%s", errors.Wrap(err, "ERROR: Could not initialize search index").Error())
 				os.Exit(1)
 			} else {
-				fmt.Fprintf(out, "WARNING: No Repo was found which containing the Chart '%s' (skipping)\n", r.Chart.Name())
+				fmt.Fprintf(out, "WARNING: No Repo was found which containing the Chart '%s' (skipping)\n", r.Chart)
 				continue
 			}
 		}
@@ -216,9 +390,11 @@ func newOutdatedListWriter(releases []*release.Release, cfg *action.Configuratio
 			outdated = append(outdated, outdatedElement{
 				Name:         r.Name,
 				Namespace:    r.Namespace,
-				InstalledVer: r.Chart.Metadata.Version,
+				InstalledVer: r.ChartVersion,
 				LatestVer:    repoResult.chart.Chart.Metadata.Version,
 				Chart:        repoResult.chart.Chart.Name,
+				Deprecated:   repoResult.chart.Chart.Deprecated,
+				BumpLevel:    computeBumpLevel(r.ChartVersion, repoResult.chart.Chart.Metadata.Version),
 			})
 		} else {
 			repoResult.repos.Namespace = r.Namespace
@@ -232,7 +408,20 @@ func newOutdatedListWriter(releases []*release.Release, cfg *action.Configuratio
 	}
 }
 
-func initSearch(out io.Writer, o *searchRepoOptions) (*search.Index, error) {
+// buildSearchIndex loads 'repositories.yaml' and builds the search index
+// used to look up newer chart versions. @devel is the only per-invocation
+// option that matters here; everything else uses the plugin's defaults.
+func buildSearchIndex(out io.Writer, devel bool) (*search.Index, error) {
+	o := &searchRepoOptions{
+		versions:     false,
+		regexp:       false,
+		devel:        devel,
+		maxColWidth:  50,
+		version:      "",
+		repoFile:     settings.RepositoryConfig,
+		repoCacheDir: settings.RepositoryCache,
+	}
+
 	index, err := o.buildIndex(out)
 	if err != nil {
 		return nil, err
@@ -241,21 +430,67 @@ func initSearch(out io.Writer, o *searchRepoOptions) (*search.Index, error) {
 	return index, nil
 }
 
+// chartIndexKey normalizes a search result name (e.g. "stable/nginx") or a
+// bare chart name (e.g. "nginx") down to the key 'buildChartIndex' groups by.
+func chartIndexKey(name string) string {
+	if i := strings.LastIndex(name, "/"); i != -1 {
+		name = name[i+1:]
+	}
+
+	return strings.ToLower(name)
+}
+
+// buildChartIndex groups @results by chart name (the suffix after '/') so
+// 'searchChart' can look a chart up in O(1) instead of scanning every
+// indexed chart for every release.
+func buildChartIndex(results []*search.Result) map[string][]*search.Result {
+	chartIndex := make(map[string][]*search.Result, len(results))
+	for _, result := range results {
+		key := chartIndexKey(result.Name)
+		chartIndex[key] = append(chartIndex[key], result)
+	}
+
+	return chartIndex
+}
+
+// mergeChartIndex re-derives @chartName's entry in @chartIndex from
+// @results, used after a repo has been dynamically registered mid-run
+// (e.g. an Argo CD Application's repoURL).
+func mergeChartIndex(chartIndex map[string][]*search.Result, results []*search.Result, chartName string) {
+	key := chartIndexKey(chartName)
+
+	var matches []*search.Result
+	for _, result := range results {
+		if chartIndexKey(result.Name) == key {
+			matches = append(matches, result)
+		}
+	}
+
+	chartIndex[key] = matches
+}
+
 // searchChart searches for Repositories which are containing that chart.
 // @name does contain the (deployed) chart named.
 //
 // It will return a struct with all search information.
 // If no results are found, nil will be returned instead of type *Result.
 // And the bool describes if it may be some Repositories contain a deprecated chart.
-func searchChart(r []*search.Result, name string, chartVersion string, devel bool) (searchResult, bool, error) {
+func searchChart(chartIndex map[string][]*search.Result, name string, chartVersion string, devel bool) (searchResult, bool, error) {
+	return searchChartResults(chartIndex[chartIndexKey(name)], name, chartVersion, devel)
+}
+
+// searchChartResults is the part of 'searchChart' that doesn't need the
+// full index, split out so a one-off fallback lookup (e.g. an OCI or
+// ArtifactHub result) can be checked against a release's own candidates
+// without writing it into the shared 'chartIndex', which would otherwise
+// leak that result to every other release sharing the same chart name.
+func searchChartResults(chartRepos []*search.Result, name string, chartVersion string, devel bool) (searchResult, bool, error) {
 	ret := searchResult{}
 
-	// since we have now to check also if a repository contains an
-	// deprecated chart we need an "point" where to look if we have found
-	// a newer chart version
-	foundNewer := false
-	found := false                  // found describes if Charts where found but no one is newer than the actual one
-	var chartRepos []*search.Result // chartRepos contains all repositories which contains the searched chart
+	if len(chartRepos) == 0 {
+		debug("Could not find any Repo which contains %s", name)
+		return ret, false, errors.New(fmt.Sprintf("Could not find any Repo which contains %s", name))
+	}
 
 	// prepare the constrain string so we do not have the re-calculate it every time
 	constrainStr := "> " + chartVersion
@@ -263,47 +498,29 @@ func searchChart(r []*search.Result, name string, chartVersion string, devel boo
 		constrainStr += "-0" + " != " + chartVersion
 	}
 
-	// TODO: implement a better search algorithm. Because this is an linear search algorithm so it takes O(len(r)) steps in the
-	// worst case
-	for _, result := range r {
-		// check if the Chart-Result Name is that one we are searching for.
-		if strings.HasSuffix(strings.ToLower(result.Name), strings.ToLower(name)) {
-			// check if Version is newer than the actual one
-			version, err := semver.NewVersion(result.Chart.Metadata.Version)
-			if err != nil {
-				return ret, false, err
-			}
-
-			constrain, err := semver.NewConstraint(constrainStr)
-			if err != nil {
-				return ret, false, err
-			}
-
-			debug("Comparing version of original chart '%s' => %s with version (%s) %s [constrain: '%s']",
-				name, chartVersion, result.Name, result.Chart.Metadata.Version, constrainStr)
-			if constrain.Check(version) {
-				debug("Found newer version '%s' %s > %s", result.Name, result.Chart.Metadata.Version, chartVersion)
-				foundNewer = true
-			}
-
-			// // TODO(l0nax): refactor me ==> @duplicate append MUST be moved out of this if-block! */
-			// if deprecationInfo {
-			//     // add this Repository to the @duplicate variable, even if the version is not newer than the current installed.
-			//     // This is because if the chart was installed at the time where the repository stopped maintaining the Chart we
-			//     // would not know it – later – that this Repo is deperecated.
-			//     chartRepos = append(chartRepos, result)
-			// }
+	constrain, err := semver.NewConstraint(constrainStr)
+	if err != nil {
+		return ret, false, err
+	}
 
-			chartRepos = append(chartRepos, result)
+	// since we have now to check also if a repository contains an
+	// deprecated chart we need an "point" where to look if we have found
+	// a newer chart version
+	foundNewer := false
 
-			// set 'found' to true because a Repository contains the Chart but the Version is not newer than the installed one.
-			found = true
+	for _, result := range chartRepos {
+		// check if Version is newer than the actual one
+		version, err := semver.NewVersion(result.Chart.Metadata.Version)
+		if err != nil {
+			return ret, false, err
 		}
-	}
 
-	if !found {
-		debug("Could not find any Repo which contains %s", name)
-		return ret, false, errors.New(fmt.Sprintf("Could not find any Repo which contains %s", name))
+		debug("Comparing version of original chart '%s' => %s with version (%s) %s [constrain: '%s']",
+			name, chartVersion, result.Name, result.Chart.Metadata.Version, constrainStr)
+		if constrain.Check(version) {
+			debug("Found newer version '%s' %s > %s", result.Name, result.Chart.Metadata.Version, chartVersion)
+			foundNewer = true
+		}
 	}
 
 	// check if we have multiple repositories which do serve the chart
@@ -320,6 +537,7 @@ func searchChart(r []*search.Result, name string, chartVersion string, devel boo
 				Chart:        c.Name,
 				Updated:      c.Chart.Created,
 				Deprecated:   c.Chart.Deprecated,
+				BumpLevel:    computeBumpLevel(chartVersion, c.Chart.Metadata.Version),
 			})
 		}
 
@@ -351,10 +569,19 @@ func searchChart(r []*search.Result, name string, chartVersion string, devel boo
 
 func (r *outdatedListWriter) WriteTable(out io.Writer) error {
 	table := uitable.New()
+	useColor := colorEnabled(out)
 
-	table.AddRow("NAME", "NAMESPACE", "INSTALLED VERSION", "LATEST VERSION", "CHART")
+	table.AddRow("NAME", "NAMESPACE", "INSTALLED VERSION", "LATEST VERSION", "CHART", "SEVERITY")
 	for _, r := range r.releases {
-		table.AddRow(r.Name, r.Namespace, r.InstalledVer, r.LatestVer, r.Chart)
+		severity := severityLabel(r.BumpLevel)
+		if r.Deprecated {
+			if severity != "" {
+				severity += " "
+			}
+			severity += "(deprecated)"
+		}
+
+		table.AddRow(r.Name, r.Namespace, r.InstalledVer, r.LatestVer, r.Chart, colorize(useColor, severityColor(r.BumpLevel), severity))
 	}
 
 	// write basic table and then add additional information if we found multiple repositories which do serve one (or more)
@@ -383,9 +610,10 @@ func (r *outdatedListWriter) WriteTable(out io.Writer) error {
 		// print repository table
 		table = uitable.New()
 
-		table.AddRow("REPOSITORY", "DEPRECATED", "CHART VERSION", "APP VERSION", "UPDATED")
+		table.AddRow("REPOSITORY", "DEPRECATED", "CHART VERSION", "APP VERSION", "UPDATED", "SEVERITY")
 		for _, r := range dc.Repos {
-			table.AddRow(strings.Split(r.Chart, "/")[0], r.Deprecated, r.LatestVer, r.AppVer, r.Updated.UTC().String())
+			severity := colorize(useColor, severityColor(r.BumpLevel), severityLabel(r.BumpLevel))
+			table.AddRow(strings.Split(r.Chart, "/")[0], r.Deprecated, r.LatestVer, r.AppVer, r.Updated.UTC().String(), severity)
 		}
 
 		err := output.EncodeTable(out, table)
@@ -398,12 +626,41 @@ func (r *outdatedListWriter) WriteTable(out io.Writer) error {
 	return err
 }
 
+// outdatedReport is the shape written by WriteJSON/WriteYAML: the releases
+// plus a summary so CI pipelines can gate on the counts alone.
+type outdatedReport struct {
+	Releases []outdatedElement `json:"releases"`
+	Summary  reportSummary     `json:"summary"`
+}
+
 func (r *outdatedListWriter) WriteJSON(out io.Writer) error {
-	return output.EncodeJSON(out, r.releases)
+	return output.EncodeJSON(out, outdatedReport{Releases: r.releases, Summary: summarize(r.policyReleases())})
 }
 
 func (r *outdatedListWriter) WriteYAML(out io.Writer) error {
-	return output.EncodeYAML(out, r.releases)
+	return output.EncodeYAML(out, outdatedReport{Releases: r.releases, Summary: summarize(r.policyReleases())})
+}
+
+// writeShortOutput renders just the release names, mirroring how upstream
+// 'helm list -q' combines with '-o': a bare name per line for 'table', and
+// a plain string list for 'json'/'yaml'.
+func writeShortOutput(out io.Writer, format output.Format, releases []outdatedElement) error {
+	names := make([]string, 0, len(releases))
+	for _, r := range releases {
+		names = append(names, r.Name)
+	}
+
+	switch format {
+	case output.JSON:
+		return output.EncodeJSON(out, names)
+	case output.YAML:
+		return output.EncodeYAML(out, names)
+	default:
+		for _, name := range names {
+			fmt.Fprintln(out, name)
+		}
+		return nil
+	}
 }
 
 /// ===== Internal required Functions ====== ///
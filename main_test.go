@@ -0,0 +1,86 @@
+/*
+Copyright The Helm Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"helm.sh/helm/v3/pkg/cli/output"
+)
+
+func TestWriteShortOutputTable(t *testing.T) {
+	releases := []outdatedElement{{Name: "my-nginx"}, {Name: "my-redis"}}
+
+	buf := &bytes.Buffer{}
+	if err := writeShortOutput(buf, output.Table, releases); err != nil {
+		t.Fatalf("writeShortOutput() error = %v", err)
+	}
+
+	if want := "my-nginx\nmy-redis\n"; buf.String() != want {
+		t.Fatalf("writeShortOutput(table) = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestWriteShortOutputJSON(t *testing.T) {
+	releases := []outdatedElement{{Name: "my-nginx"}, {Name: "my-redis"}}
+
+	buf := &bytes.Buffer{}
+	if err := writeShortOutput(buf, output.JSON, releases); err != nil {
+		t.Fatalf("writeShortOutput() error = %v", err)
+	}
+
+	var got []string
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("writeShortOutput(json) produced invalid JSON: %v\noutput: %s", err, buf.String())
+	}
+
+	want := []string{"my-nginx", "my-redis"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("writeShortOutput(json) = %v, want %v", got, want)
+	}
+}
+
+func TestWriteShortOutputYAML(t *testing.T) {
+	releases := []outdatedElement{{Name: "my-nginx"}, {Name: "my-redis"}}
+
+	buf := &bytes.Buffer{}
+	if err := writeShortOutput(buf, output.YAML, releases); err != nil {
+		t.Fatalf("writeShortOutput() error = %v", err)
+	}
+
+	var got []string
+	if err := yaml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("writeShortOutput(yaml) produced invalid YAML: %v\noutput: %s", err, buf.String())
+	}
+
+	want := []string{"my-nginx", "my-redis"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("writeShortOutput(yaml) = %v, want %v", got, want)
+	}
+}
+
+func TestWriteShortOutputNoReleases(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := writeShortOutput(buf, output.Table, nil); err != nil {
+		t.Fatalf("writeShortOutput() error = %v", err)
+	}
+	if buf.String() != "" {
+		t.Fatalf("writeShortOutput(table) with no releases = %q, want empty", buf.String())
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright The Helm Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+
+	"helm.sh/helm/v3/cmd/helm/search"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/registry"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+// ociRegistries is the raw value of the repeatable '--oci-registry' flag: a
+// list of "host/repository" prefixes to try for a chart that didn't declare
+// its own OCI provenance.
+var ociRegistries []string
+
+// ociProvenanceAnnotation is the Chart.yaml annotation this plugin looks
+// for to recover the 'oci://' ref a release was installed from, since
+// Helm does not record that on the release itself.
+const ociProvenanceAnnotation = "helm.sh/oci-ref"
+
+// ociResolver looks up the latest semver tag for a chart that was installed
+// from an 'oci://' reference (increasingly the default for Helm 3.8+), so
+// it can be diffed the same way as charts coming from classic HTTP
+// repositories. Tag listing and authentication both go through Helm's own
+// registry client (itself backed by oras-go), so private ACR/ECR/GHCR
+// registries work with whatever credentials 'helm registry login' already
+// stored.
+type ociResolver struct {
+	client *registry.Client
+}
+
+func newOCIResolver() (*ociResolver, error) {
+	client, err := registry.NewClient(registry.ClientOptCredentialsFile(settings.RegistryConfig))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create OCI registry client")
+	}
+
+	return &ociResolver{client: client}, nil
+}
+
+// Resolve lists the tags published under @ref (e.g.
+// "registry.example.com/charts/mychart"), keeps the ones that parse as
+// semver and satisfy the same devel/constraint rules 'searchChart' uses,
+// and returns a synthetic search.Result for the newest matching one.
+func (o *ociResolver) Resolve(ref, chartName, installedVersion string, devel bool) (*search.Result, error) {
+	tags, err := o.client.Tags(strings.TrimPrefix(ref, "oci://"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not list tags for %q", ref)
+	}
+
+	constrainStr := "> " + installedVersion
+	if devel {
+		constrainStr += "-0" + " != " + installedVersion
+	}
+
+	constrain, err := semver.NewConstraint(constrainStr)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *semver.Version
+	for _, tag := range tags {
+		v, err := semver.NewVersion(tag)
+		if err != nil {
+			// not every tag is a chart version (e.g. cosign signatures, SBOMs); skip silently
+			continue
+		}
+
+		if !constrain.Check(v) {
+			continue
+		}
+
+		if latest == nil || v.GreaterThan(latest) {
+			latest = v
+		}
+	}
+
+	if latest == nil {
+		return nil, errors.Errorf("no newer semver tag found for %q", ref)
+	}
+
+	return &search.Result{
+		Name: ref,
+		Chart: &repo.ChartVersion{
+			Metadata: &chart.Metadata{
+				Name:    chartName,
+				Version: latest.Original(),
+			},
+		},
+	}, nil
+}
+
+// ociRefCandidates returns the OCI refs worth trying for @chartName: first
+// whatever provenance the chart itself declares, then every configured
+// '--oci-registry' prefix.
+func ociRefCandidates(chartName string, annotations map[string]string) []string {
+	var candidates []string
+
+	if ref, ok := annotations[ociProvenanceAnnotation]; ok && ref != "" {
+		candidates = append(candidates, ref)
+	}
+
+	for _, registryRef := range ociRegistries {
+		candidates = append(candidates, strings.TrimSuffix(registryRef, "/")+"/"+chartName)
+	}
+
+	return candidates
+}
@@ -0,0 +1,56 @@
+/*
+Copyright The Helm Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOciRefCandidates(t *testing.T) {
+	t.Cleanup(func() { ociRegistries = nil })
+
+	ociRegistries = []string{"ghcr.io/acme/charts", "registry.example.com/charts/"}
+
+	got := ociRefCandidates("nginx", map[string]string{ociProvenanceAnnotation: "oci://ghcr.io/acme/charts/nginx"})
+	want := []string{
+		"oci://ghcr.io/acme/charts/nginx",
+		"ghcr.io/acme/charts/nginx",
+		"registry.example.com/charts/nginx",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ociRefCandidates() = %v, want %v", got, want)
+	}
+}
+
+func TestOciRefCandidatesNoProvenance(t *testing.T) {
+	t.Cleanup(func() { ociRegistries = nil })
+
+	ociRegistries = []string{"ghcr.io/acme/charts"}
+
+	got := ociRefCandidates("nginx", nil)
+	want := []string{"ghcr.io/acme/charts/nginx"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ociRefCandidates() = %v, want %v", got, want)
+	}
+}
+
+func TestOciRefCandidatesNone(t *testing.T) {
+	t.Cleanup(func() { ociRegistries = nil })
+	ociRegistries = nil
+
+	if got := ociRefCandidates("nginx", nil); got != nil {
+		t.Fatalf("ociRefCandidates() = %v, want nil with no annotation and no --oci-registry", got)
+	}
+}
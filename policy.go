@@ -0,0 +1,139 @@
+/*
+Copyright The Helm Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+)
+
+// bumpLevel describes the semver category of the jump between an installed
+// and a latest chart version.
+type bumpLevel string
+
+const (
+	bumpNone  bumpLevel = ""
+	bumpPatch bumpLevel = "patch"
+	bumpMinor bumpLevel = "minor"
+	bumpMajor bumpLevel = "major"
+)
+
+// rank orders bump levels by severity so '--fail-on' can do a ">=" comparison.
+func (b bumpLevel) rank() int {
+	switch b {
+	case bumpPatch:
+		return 1
+	case bumpMinor:
+		return 2
+	case bumpMajor:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// computeBumpLevel diffs @installedVer against @latestVer and reports
+// whether upgrading is a major, minor, or patch bump. It returns
+// 'bumpNone' if either version fails to parse as semver, since the rest of
+// the plugin already tolerates non-semver chart versions elsewhere.
+func computeBumpLevel(installedVer, latestVer string) bumpLevel {
+	installed, err := semver.NewVersion(installedVer)
+	if err != nil {
+		return bumpNone
+	}
+
+	latest, err := semver.NewVersion(latestVer)
+	if err != nil {
+		return bumpNone
+	}
+
+	switch {
+	case latest.Major() != installed.Major():
+		return bumpMajor
+	case latest.Minor() != installed.Minor():
+		return bumpMinor
+	case latest.Patch() != installed.Patch():
+		return bumpPatch
+	default:
+		return bumpNone
+	}
+}
+
+var (
+	failOnOutdated   bool   = false
+	failOnLevel      string = ""
+	failOnDeprecated bool   = false
+)
+
+// reportSummary carries aggregate counts over a report's releases so CI
+// pipelines can gate on it without re-parsing every entry.
+type reportSummary struct {
+	Total      int `json:"total"`
+	Major      int `json:"major"`
+	Minor      int `json:"minor"`
+	Patch      int `json:"patch"`
+	Deprecated int `json:"deprecated"`
+}
+
+// summarize builds a reportSummary over @releases.
+func summarize(releases []outdatedElement) reportSummary {
+	s := reportSummary{Total: len(releases)}
+
+	for _, r := range releases {
+		switch r.BumpLevel {
+		case bumpMajor:
+			s.Major++
+		case bumpMinor:
+			s.Minor++
+		case bumpPatch:
+			s.Patch++
+		}
+
+		if r.Deprecated {
+			s.Deprecated++
+		}
+	}
+
+	return s
+}
+
+// evaluatePolicy checks @releases against the '--fail-on*' flags and
+// returns a non-nil error if the report should cause a non-zero exit code,
+// e.g. so a CI pipeline can gate promotions on drift-detection results.
+func evaluatePolicy(releases []outdatedElement) error {
+	if !failOnOutdated && failOnLevel == "" && !failOnDeprecated {
+		return nil
+	}
+
+	threshold := bumpLevel(failOnLevel)
+	if failOnLevel != "" && threshold.rank() == 0 {
+		return errors.Errorf("invalid --fail-on %q: must be one of major, minor, patch", failOnLevel)
+	}
+
+	for _, r := range releases {
+		if failOnOutdated {
+			return errors.Errorf("release %q is outdated (%s -> %s)", r.Name, r.InstalledVer, r.LatestVer)
+		}
+
+		if threshold.rank() > 0 && r.BumpLevel.rank() >= threshold.rank() {
+			return errors.Errorf("release %q has a %s version bump available (%s -> %s)", r.Name, r.BumpLevel, r.InstalledVer, r.LatestVer)
+		}
+
+		if failOnDeprecated && r.Deprecated {
+			return errors.Errorf("release %q uses a deprecated chart", r.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright The Helm Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import "testing"
+
+func TestComputeBumpLevel(t *testing.T) {
+	cases := []struct {
+		installed, latest string
+		want              bumpLevel
+	}{
+		{"1.0.0", "2.0.0", bumpMajor},
+		{"1.0.0", "1.1.0", bumpMinor},
+		{"1.0.0", "1.0.1", bumpPatch},
+		{"1.0.0", "1.0.0", bumpNone},
+		{"not-semver", "1.0.0", bumpNone},
+		{"1.0.0", "not-semver", bumpNone},
+	}
+
+	for _, c := range cases {
+		if got := computeBumpLevel(c.installed, c.latest); got != c.want {
+			t.Errorf("computeBumpLevel(%q, %q) = %q, want %q", c.installed, c.latest, got, c.want)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	releases := []outdatedElement{
+		{Name: "a", BumpLevel: bumpMajor},
+		{Name: "b", BumpLevel: bumpMinor},
+		{Name: "c", BumpLevel: bumpPatch, Deprecated: true},
+		{Name: "d", BumpLevel: bumpNone},
+	}
+
+	got := summarize(releases)
+	want := reportSummary{Total: 4, Major: 1, Minor: 1, Patch: 1, Deprecated: 1}
+	if got != want {
+		t.Fatalf("summarize() = %+v, want %+v", got, want)
+	}
+}
+
+func TestEvaluatePolicyDisabledByDefault(t *testing.T) {
+	releases := []outdatedElement{{Name: "a", BumpLevel: bumpMajor}}
+	if err := evaluatePolicy(releases); err != nil {
+		t.Fatalf("expected nil error when no --fail-on* flag is set, got %v", err)
+	}
+}
+
+func TestEvaluatePolicyFailOnOutdated(t *testing.T) {
+	t.Cleanup(func() { failOnOutdated = false })
+	failOnOutdated = true
+
+	if err := evaluatePolicy([]outdatedElement{{Name: "a"}}); err == nil {
+		t.Fatal("expected an error for a non-empty release list")
+	}
+	if err := evaluatePolicy(nil); err != nil {
+		t.Fatalf("expected nil error for an empty release list, got %v", err)
+	}
+}
+
+func TestEvaluatePolicyFailOnLevel(t *testing.T) {
+	t.Cleanup(func() { failOnLevel = "" })
+	failOnLevel = "minor"
+
+	if err := evaluatePolicy([]outdatedElement{{Name: "a", BumpLevel: bumpPatch}}); err != nil {
+		t.Fatalf("patch bump should not trip a 'minor' threshold, got %v", err)
+	}
+	if err := evaluatePolicy([]outdatedElement{{Name: "a", BumpLevel: bumpMinor}}); err == nil {
+		t.Fatal("minor bump should trip a 'minor' threshold")
+	}
+	if err := evaluatePolicy([]outdatedElement{{Name: "a", BumpLevel: bumpMajor}}); err == nil {
+		t.Fatal("major bump should trip a 'minor' threshold")
+	}
+}
+
+func TestEvaluatePolicyInvalidLevel(t *testing.T) {
+	t.Cleanup(func() { failOnLevel = "" })
+	failOnLevel = "bogus"
+
+	if err := evaluatePolicy([]outdatedElement{{Name: "a"}}); err == nil {
+		t.Fatal("expected an error for an invalid --fail-on value")
+	}
+}
+
+func TestEvaluatePolicyFailOnDeprecated(t *testing.T) {
+	t.Cleanup(func() { failOnDeprecated = false })
+	failOnDeprecated = true
+
+	if err := evaluatePolicy([]outdatedElement{{Name: "a", Deprecated: true}}); err == nil {
+		t.Fatal("expected an error for a deprecated release")
+	}
+	if err := evaluatePolicy([]outdatedElement{{Name: "a", Deprecated: false}}); err != nil {
+		t.Fatalf("non-deprecated release should not trip --fail-on-deprecated, got %v", err)
+	}
+}
+
+// TestPolicyReleasesIncludesRepoDuplicates guards the chunk0-4 fix: charts
+// served by more than one repo land in repoDuplicates instead of releases,
+// but must still be visible to --fail-on* and the summary.
+func TestPolicyReleasesIncludesRepoDuplicates(t *testing.T) {
+	w := &outdatedListWriter{
+		releases: []outdatedElement{{Name: "a", BumpLevel: bumpPatch}},
+		repoDuplicates: []repoDuplicate{
+			{
+				Name: "b",
+				Repos: []outdatedElement{
+					{Name: "b", Chart: "stable/b", BumpLevel: bumpMajor},
+					{Name: "b", Chart: "bitnami/b", BumpLevel: bumpMinor, Deprecated: true},
+				},
+			},
+		},
+	}
+
+	all := w.policyReleases()
+	if len(all) != 3 {
+		t.Fatalf("expected 3 combined releases, got %d", len(all))
+	}
+
+	summary := summarize(all)
+	if summary.Major != 1 || summary.Minor != 1 || summary.Patch != 1 || summary.Deprecated != 1 {
+		t.Fatalf("summary did not account for repoDuplicates entries: %+v", summary)
+	}
+
+	t.Cleanup(func() { failOnLevel = "" })
+	failOnLevel = "major"
+	if err := evaluatePolicy(all); err == nil {
+		t.Fatal("expected --fail-on=major to trip on a repoDuplicates entry")
+	}
+}
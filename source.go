@@ -0,0 +1,267 @@
+/*
+Copyright The Helm Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"helm.sh/helm/v3/cmd/helm/search"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/repo"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// releaseSource is the name of a value accepted by the '--source' flag.
+type releaseSource string
+
+const (
+	sourceHelm   releaseSource = "helm"
+	sourceArgoCD releaseSource = "argocd"
+	sourceAll    releaseSource = "all"
+)
+
+// releaseSeed is a normalized, source-agnostic view of a deployed (or
+// GitOps-managed) chart. It carries just enough information for
+// 'searchChart' to run, regardless of whether it was discovered via the
+// Helm storage driver or an Argo CD 'Application'.
+type releaseSeed struct {
+	Name         string
+	Namespace    string
+	Chart        string
+	ChartVersion string
+
+	// RepoURL is only set if the source knows the chart's repository
+	// without it being present in 'repositories.yaml' (e.g. Argo CD).
+	// If non-empty, it is registered in the search index before
+	// 'searchChart' is run.
+	RepoURL string
+
+	// Annotations carries the installed chart's Chart.yaml annotations, so
+	// e.g. an OCI provenance annotation can be recovered for charts
+	// installed from an 'oci://' ref.
+	Annotations map[string]string
+}
+
+// ReleaseSource discovers releases which should be checked for being
+// outdated. Implementations are free to read from the cluster, from Helm's
+// storage driver, or from declarative manifests on disk.
+type ReleaseSource interface {
+	// Load returns the normalized releases this source knows about.
+	Load() ([]releaseSeed, error)
+}
+
+// helmReleaseSource discovers releases via Helm's storage driver
+// ('action.List'), i.e. the classic behavior of this plugin.
+type helmReleaseSource struct {
+	releases []*release.Release
+}
+
+func newHelmReleaseSource(releases []*release.Release) *helmReleaseSource {
+	return &helmReleaseSource{releases: releases}
+}
+
+func (s *helmReleaseSource) Load() ([]releaseSeed, error) {
+	seeds := make([]releaseSeed, 0, len(s.releases))
+	for _, r := range s.releases {
+		seeds = append(seeds, releaseSeed{
+			Name:         r.Name,
+			Namespace:    r.Namespace,
+			Chart:        r.Chart.Name(),
+			ChartVersion: r.Chart.Metadata.Version,
+			Annotations:  r.Chart.Metadata.Annotations,
+		})
+	}
+
+	return seeds, nil
+}
+
+// argoApplicationGVR is the GroupVersionResource of Argo CD's 'Application'
+// custom resource.
+var argoApplicationGVR = schema.GroupVersionResource{
+	Group:    "argoproj.io",
+	Version:  "v1alpha1",
+	Resource: "applications",
+}
+
+// argoReleaseSource discovers releases from Argo CD 'Application' CRDs so
+// that GitOps-managed charts can be reported on without ever being
+// installed as Helm releases. @namespace is independent of the '-n' flag
+// used for Helm release listing, since Applications are usually managed in
+// a dedicated control-plane namespace (e.g. "argocd") rather than the
+// namespace(s) they deploy to; an empty @namespace lists cluster-wide.
+type argoReleaseSource struct {
+	client    dynamic.Interface
+	namespace string
+	out       io.Writer
+}
+
+func newArgoReleaseSource(client dynamic.Interface, namespace string, out io.Writer) *argoReleaseSource {
+	return &argoReleaseSource{client: client, namespace: namespace, out: out}
+}
+
+func (s *argoReleaseSource) Load() ([]releaseSeed, error) {
+	list, err := s.client.Resource(argoApplicationGVR).Namespace(s.namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list Argo CD Applications")
+	}
+
+	seeds := make([]releaseSeed, 0, len(list.Items))
+	for _, item := range list.Items {
+		source, found, err := unstructuredNestedMap(item.Object, "spec", "source")
+		if err != nil || !found {
+			fmt.Fprintf(s.out, "WARNING: Argo CD Application %q has no 'spec.source' (skipping)\n", item.GetName())
+			continue
+		}
+
+		chart, _ := source["chart"].(string)
+		repoURL, _ := source["repoURL"].(string)
+		targetRevision, _ := source["targetRevision"].(string)
+
+		if chart == "" || repoURL == "" {
+			// Not a Helm chart source (e.g. a plain git/kustomize Application).
+			continue
+		}
+
+		seeds = append(seeds, releaseSeed{
+			Name:         item.GetName(),
+			Namespace:    item.GetNamespace(),
+			Chart:        chart,
+			ChartVersion: targetRevision,
+			RepoURL:      repoURL,
+		})
+	}
+
+	return seeds, nil
+}
+
+// unstructuredNestedMap is a thin helper to dig a nested map out of the
+// unstructured object returned by the dynamic client, without pulling in the
+// whole 'unstructured' helper package for a single lookup.
+func unstructuredNestedMap(obj map[string]interface{}, fields ...string) (map[string]interface{}, bool, error) {
+	cur := obj
+	for i, field := range fields {
+		val, ok := cur[field]
+		if !ok {
+			return nil, false, nil
+		}
+
+		m, ok := val.(map[string]interface{})
+		if !ok {
+			return nil, false, errors.Errorf("%q is not a map", field)
+		}
+
+		if i == len(fields)-1 {
+			return m, true, nil
+		}
+		cur = m
+	}
+
+	return nil, false, nil
+}
+
+// registerDynamicRepo injects @repoURL into the search index under a
+// synthetic, stable repo name derived from @repoURL itself (not the chart
+// name), so that results coming from it are indistinguishable from a repo
+// configured in 'repositories.yaml'. @registered tracks repo URLs already
+// indexed this run, keyed by the URL rather than the chart name: two Argo CD
+// Applications can easily share a generic chart name (e.g. "nginx") while
+// pointing at different repos, and keying on the chart name alone would
+// make the second Application's repo look "already registered" and silently
+// compare it against the first Application's repo contents instead.
+func registerDynamicRepo(index *search.Index, registered map[string]string, repoURL string) (string, error) {
+	if repoName, ok := registered[repoURL]; ok {
+		return repoName, nil
+	}
+
+	repoName := dynamicRepoName(repoURL)
+	if err := addRepoToIndex(index, repoName, repoURL); err != nil {
+		return "", err
+	}
+
+	registered[repoURL] = repoName
+	return repoName, nil
+}
+
+// dynamicRepoName derives a stable repo alias for @repoURL so that distinct
+// repos serving the same bare chart name are indexed (and later compared)
+// independently instead of colliding under a single alias.
+func dynamicRepoName(repoURL string) string {
+	sum := sha256.Sum256([]byte(repoURL))
+	return "argocd-" + hex.EncodeToString(sum[:])[:12]
+}
+
+// indexHasRepo reports whether @index already contains results served
+// under the repo alias @repoName. Unlike the Argo CD path above, callers of
+// this helper (e.g. the helmfile source) deal in explicitly user-named
+// repos, so a name-based check can't collide the way keying Argo CD's
+// synthetic per-chart alias by name did.
+func indexHasRepo(index *search.Index, repoName string) bool {
+	for _, existing := range index.All() {
+		if strings.HasPrefix(existing.Name, repoName+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addRepoToIndex downloads the index file for @repoURL and registers it
+// under @repoName, the same way 'searchRepoOptions.buildIndex' does for
+// repos configured in 'repositories.yaml'.
+func addRepoToIndex(index *search.Index, repoName, repoURL string) error {
+	chartRepo, err := repo.NewChartRepository(&repo.Entry{
+		Name: repoName,
+		URL:  repoURL,
+	}, getter.All(settings))
+	if err != nil {
+		return errors.Wrapf(err, "could not create chart repository for %q", repoURL)
+	}
+
+	idxFile, err := chartRepo.DownloadIndexFile()
+	if err != nil {
+		return errors.Wrapf(err, "could not download index for %q", repoURL)
+	}
+
+	ind, err := repo.LoadIndexFile(idxFile)
+	if err != nil {
+		return errors.Wrapf(err, "could not load downloaded index for %q", repoURL)
+	}
+
+	index.AddRepo(repoName, ind, true)
+	return nil
+}
+
+// newDynamicClientForArgo builds a dynamic client from the plugin's existing
+// 'settings.RESTClientGetter()', reusing the same kubeconfig/context
+// resolution as the rest of the plugin so no additional flags are needed to
+// reach the cluster.
+func newDynamicClientForArgo() (dynamic.Interface, error) {
+	restCfg, err := settings.RESTClientGetter().ToRESTConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not build REST config")
+	}
+
+	return dynamic.NewForConfig(restCfg)
+}
@@ -0,0 +1,196 @@
+/*
+Copyright The Helm Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v3/cmd/helm/search"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func TestUnstructuredNestedMap(t *testing.T) {
+	obj := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"source": map[string]interface{}{
+				"chart": "nginx",
+			},
+		},
+	}
+
+	got, found, err := unstructuredNestedMap(obj, "spec", "source")
+	if err != nil || !found {
+		t.Fatalf("unstructuredNestedMap() = %v, found=%v, err=%v", got, found, err)
+	}
+	if got["chart"] != "nginx" {
+		t.Fatalf("unstructuredNestedMap() = %v, want chart=nginx", got)
+	}
+
+	if _, found, err := unstructuredNestedMap(obj, "spec", "missing"); err != nil || found {
+		t.Fatalf("expected (false, nil) for a missing field, got found=%v err=%v", found, err)
+	}
+
+	if _, _, err := unstructuredNestedMap(obj, "spec", "source", "chart"); err == nil {
+		t.Fatal("expected an error when a traversed field is not a map")
+	}
+}
+
+func newUnstructuredApplication(name, namespace string, source map[string]interface{}) *unstructured.Unstructured {
+	obj := map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Application",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": namespace,
+		},
+	}
+	if source != nil {
+		obj["spec"] = map[string]interface{}{"source": source}
+	}
+
+	return &unstructured.Unstructured{Object: obj}
+}
+
+func TestArgoReleaseSourceLoad(t *testing.T) {
+	scheme := runtime.NewScheme()
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(scheme,
+		map[schema.GroupVersionResource]string{argoApplicationGVR: "ApplicationList"},
+		newUnstructuredApplication("my-nginx", "argocd", map[string]interface{}{
+			"chart":          "nginx",
+			"repoURL":        "https://charts.example.com/a",
+			"targetRevision": "1.2.0",
+		}),
+		newUnstructuredApplication("git-app", "argocd", map[string]interface{}{
+			"path":    "manifests",
+			"repoURL": "https://git.example.com/infra.git",
+		}),
+		newUnstructuredApplication("no-source", "argocd", nil),
+	)
+
+	out := &bytes.Buffer{}
+	seeds, err := newArgoReleaseSource(client, "argocd", out).Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(seeds) != 1 {
+		t.Fatalf("Load() = %v, want exactly the Helm-chart Application", seeds)
+	}
+
+	got := seeds[0]
+	if got.Name != "my-nginx" || got.Chart != "nginx" || got.RepoURL != "https://charts.example.com/a" || got.ChartVersion != "1.2.0" {
+		t.Fatalf("unexpected seed: %+v", got)
+	}
+
+	if !strings.Contains(out.String(), `"no-source"`) {
+		t.Fatalf("expected a warning about the Application with no 'spec.source', got: %s", out.String())
+	}
+}
+
+func TestDynamicRepoName(t *testing.T) {
+	a := dynamicRepoName("https://charts.example.com/a")
+	b := dynamicRepoName("https://charts.example.com/b")
+
+	if a == b {
+		t.Fatalf("dynamicRepoName() returned the same name for two different repoURLs: %q", a)
+	}
+	if dynamicRepoName("https://charts.example.com/a") != a {
+		t.Fatal("dynamicRepoName() is not stable for the same repoURL")
+	}
+}
+
+func newTestIndexServer(t *testing.T, version string) *httptest.Server {
+	t.Helper()
+
+	indexYAML := `apiVersion: v1
+entries:
+  nginx:
+    - name: nginx
+      version: ` + version + `
+      urls:
+        - nginx-` + version + `.tgz
+`
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(indexYAML))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+// TestRegisterDynamicRepoKeysByURL guards the chunk0-1 fix: two repos
+// serving the same bare chart name ("nginx") under different repoURLs must
+// each be indexed under their own alias, not collide into a single one.
+func TestRegisterDynamicRepoKeysByURL(t *testing.T) {
+	srvA := newTestIndexServer(t, "1.2.0")
+	srvB := newTestIndexServer(t, "1.5.0")
+
+	index := search.NewIndex()
+	registered := make(map[string]string)
+
+	nameA, err := registerDynamicRepo(index, registered, srvA.URL)
+	if err != nil {
+		t.Fatalf("registerDynamicRepo(A) error = %v", err)
+	}
+	nameB, err := registerDynamicRepo(index, registered, srvB.URL)
+	if err != nil {
+		t.Fatalf("registerDynamicRepo(B) error = %v", err)
+	}
+
+	if nameA == nameB {
+		t.Fatalf("expected distinct repo names for distinct repoURLs, got %q for both", nameA)
+	}
+
+	idx := buildChartIndex(index.All())
+	if len(idx["nginx"]) != 2 {
+		t.Fatalf("expected both repos' 'nginx' entries indexed independently, got %d", len(idx["nginx"]))
+	}
+}
+
+// TestRegisterDynamicRepoReusesURL makes sure the same repoURL is only
+// downloaded and registered once, even across repeated calls (e.g. many
+// releases pointing at the same Argo CD repo).
+func TestRegisterDynamicRepoReusesURL(t *testing.T) {
+	srv := newTestIndexServer(t, "1.2.0")
+
+	index := search.NewIndex()
+	registered := make(map[string]string)
+
+	name1, err := registerDynamicRepo(index, registered, srv.URL)
+	if err != nil {
+		t.Fatalf("registerDynamicRepo() error = %v", err)
+	}
+	name2, err := registerDynamicRepo(index, registered, srv.URL)
+	if err != nil {
+		t.Fatalf("registerDynamicRepo() error = %v", err)
+	}
+
+	if name1 != name2 {
+		t.Fatalf("expected the same repoURL to reuse its registered name, got %q and %q", name1, name2)
+	}
+
+	idx := buildChartIndex(index.All())
+	if len(idx["nginx"]) != 1 {
+		t.Fatalf("expected the repo to be indexed exactly once, got %d entries", len(idx["nginx"]))
+	}
+}